@@ -0,0 +1,37 @@
+//go:build !prod
+
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/panyam/notations/docs/listenfd"
+)
+
+// run implements the development flow: rebuild the site, watch the content
+// tree for changes, and serve the result with no production hardening.
+func run() {
+	Site.Rebuild(nil)
+	generateFeeds()
+	if *build {
+		return
+	}
+	Site.Watch()
+	serveSite(*addr)
+}
+
+// serveSite starts a plain http.Server over buildHandler(). There is no
+// graceful shutdown here; that only matters for the prod binary.
+func serveSite(address string) {
+	ln, err := listenfd.Listen(address, *listenFD)
+	if err != nil {
+		log.Fatal("Could not acquire a listener: ", err)
+	}
+
+	srv := &http.Server{
+		Handler: buildHandler(),
+	}
+	log.Printf("Serving site on %s:", ln.Addr())
+	log.Fatal(srv.Serve(ln))
+}
@@ -0,0 +1,223 @@
+// Package feed generates Atom and RSS feeds for the release notes and
+// changelog entries published under a site's content tree.
+package feed
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+
+	s3 "github.com/panyam/s3gen"
+)
+
+// entryDirs are the content subtrees scanned for feed entries, tried in
+// order; the first one that exists is used.
+var entryDirs = []string{"releases", "changelog"}
+
+// frontMatter is the metadata expected on every feed entry.
+type frontMatter struct {
+	Title   string `yaml:"title"`
+	Date    string `yaml:"date"`
+	Tag     string `yaml:"tag"`
+	Summary string `yaml:"summary"`
+}
+
+// Entry is a single, rendered feed item.
+type Entry struct {
+	ID      string
+	Title   string
+	Date    time.Time
+	Tag     string
+	Summary string
+	Content template.HTML
+
+	// URLPath is the path, relative to the site's PathPrefix, of the entry's
+	// rendered HTML page (not its raw content file), used to build its
+	// canonical URL. The site renders "a/b/c.md" to "a/b/c/index.html", so
+	// URLPath is "a/b/c/".
+	URLPath string
+}
+
+// Generator renders feed.atom and feed.xml from a site's release notes and
+// changelog entries.
+type Generator struct {
+	// Site is the s3gen site whose content is being fed and whose
+	// OutputDir/PathPrefix the feed files are written/linked under.
+	Site *s3.Site
+
+	// Domain is the authority name used in entry tag URIs (RFC 4151), e.g.
+	// "notations.dev".
+	Domain string
+
+	// StartDate anchors the tag URIs so they stay valid even if Domain
+	// changes ownership later; RFC 4151 recommends the date the domain (or
+	// feed) came under the publisher's control, formatted as "2006-01-02".
+	StartDate string
+
+	// Title is the feed's display title. Defaults to Domain + Site.PathPrefix
+	// if left empty.
+	Title string
+
+	// StylesheetURL, if set, is emitted as an <?xml-stylesheet?> processing
+	// instruction so the feed renders nicely when opened directly in a
+	// browser.
+	StylesheetURL string
+}
+
+// NewGenerator returns a Generator for site, anchoring tag URIs at domain and
+// startDate (format "2006-01-02").
+func NewGenerator(site *s3.Site, domain, startDate string) *Generator {
+	return &Generator{Site: site, Domain: domain, StartDate: startDate}
+}
+
+// Generate scans the site's content tree for release/changelog entries and
+// writes feed.atom and feed.xml into OutputDir. It is a no-op (not an error)
+// if neither a releases/ nor changelog/ subtree exists, or if that subtree
+// holds no entry with a parseable date.
+func (g *Generator) Generate() error {
+	entries, err := g.collectEntries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Date.After(entries[j].Date) })
+
+	if err := g.writeAtom(entries); err != nil {
+		return err
+	}
+	return g.writeRSS(entries)
+}
+
+func (g *Generator) collectEntries() ([]Entry, error) {
+	var dir string
+	for _, candidate := range entryDirs {
+		full := filepath.Join(g.Site.ContentRoot, candidate)
+		if info, err := os.Stat(full); err == nil && info.IsDir() {
+			dir = full
+			break
+		}
+	}
+	if dir == "" {
+		return nil, nil
+	}
+
+	var entries []Entry
+	walkErr := filepath.WalkDir(dir, func(fullpath string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(fullpath)
+		if ext != ".md" && ext != ".mdx" {
+			return nil
+		}
+
+		f, err := os.Open(fullpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var meta frontMatter
+		rest, err := frontmatter.Parse(f, &meta)
+		if err != nil {
+			return err
+		}
+
+		date, err := time.Parse("2006-01-02", meta.Date)
+		if err != nil {
+			// Skip entries we can't date rather than failing the whole build.
+			return nil
+		}
+
+		content, err := renderMarkdown(rest)
+		if err != nil {
+			return err
+		}
+
+		relpath, err := filepath.Rel(g.Site.ContentRoot, fullpath)
+		if err != nil {
+			relpath = fullpath
+		}
+
+		entries = append(entries, Entry{
+			ID:      g.tagURI(meta.Tag),
+			Title:   meta.Title,
+			Date:    date,
+			Tag:     meta.Tag,
+			Summary: meta.Summary,
+			Content: content,
+			URLPath: renderedURLPath(relpath),
+		})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return entries, nil
+}
+
+// renderedURLPath converts a content-relative path like "releases/v1.2.0.md"
+// into the clean URL the site actually serves that page at, matching
+// s3gen's own rule for rendering "<ContentRoot>/a/b/c.md" to
+// "<OutputDir>/a/b/c/index.html": the extension is dropped and, unless the
+// file is itself an index page, its basename becomes a directory segment.
+func renderedURLPath(relpath string) string {
+	rem := strings.TrimSuffix(relpath, filepath.Ext(relpath))
+	dir := filepath.Dir(rem)
+	switch filepath.Base(rem) {
+	case "index", "_index", "Index":
+		rem = dir
+	}
+	if rem == "." {
+		return ""
+	}
+	return filepath.ToSlash(rem) + "/"
+}
+
+// tagURI builds a stable RFC 4151 tag URI of the form
+// "tag:<domain>,<start-date>:releases/<tag>" for a release/changelog entry.
+func (g *Generator) tagURI(tag string) string {
+	return "tag:" + g.Domain + "," + g.StartDate + ":releases/" + tag
+}
+
+// renderMarkdown runs markdown through the same goldmark pipeline the rest
+// of the site uses for content, so feed entries look like their rendered
+// page.
+func renderMarkdown(source []byte) (template.HTML, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Strikethrough,
+			extension.Typographer,
+			highlighting.NewHighlighting(highlighting.WithStyle("monokai")),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(
+			html.WithHardWraps(),
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+	)
+
+	var buf bytes.Buffer
+	if err := md.Convert(source, &buf); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
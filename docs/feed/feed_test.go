@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	s3 "github.com/panyam/s3gen"
+)
+
+func writeRelease(t *testing.T, dir, name, title, date, tag, summary, body string) {
+	t.Helper()
+	content := "---\n" +
+		"title: " + title + "\n" +
+		"date: " + date + "\n" +
+		"tag: " + tag + "\n" +
+		"summary: " + summary + "\n" +
+		"---\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGeneratorWritesAtomAndRSS(t *testing.T) {
+	contentRoot := t.TempDir()
+	outputDir := t.TempDir()
+	releases := filepath.Join(contentRoot, "releases")
+	if err := os.MkdirAll(releases, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeRelease(t, releases, "v1.2.0.md", "v1.2.0", "2026-01-15", "v1.2.0", "Adds widgets", "# v1.2.0\n\nAdds **widgets**.")
+	writeRelease(t, releases, "v1.1.0.md", "v1.1.0", "2025-11-01", "v1.1.0", "Bug fixes", "Fixed a bug.")
+
+	site := &s3.Site{ContentRoot: contentRoot, OutputDir: outputDir, PathPrefix: "/notations"}
+	gen := NewGenerator(site, "notations.dev", "2024-01-01")
+
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	atom, err := os.ReadFile(filepath.Join(outputDir, "feed.atom"))
+	if err != nil {
+		t.Fatalf("feed.atom not written: %v", err)
+	}
+	atomStr := string(atom)
+	if !strings.Contains(atomStr, "tag:notations.dev,2024-01-01:releases/v1.2.0") {
+		t.Fatalf("expected stable tag URI for v1.2.0 entry, got:\n%s", atomStr)
+	}
+	if strings.Index(atomStr, "v1.2.0") > strings.Index(atomStr, "v1.1.0") {
+		t.Fatalf("expected newest entry (v1.2.0) first, got:\n%s", atomStr)
+	}
+	wantLink := `<link href="https://notations.dev/notations/releases/v1.2.0/"/>`
+	if !strings.Contains(atomStr, wantLink) {
+		t.Fatalf("expected entry link to point at the rendered page (no .md, trailing slash), got:\n%s", atomStr)
+	}
+
+	rss, err := os.ReadFile(filepath.Join(outputDir, "feed.xml"))
+	if err != nil {
+		t.Fatalf("feed.xml not written: %v", err)
+	}
+	rssStr := string(rss)
+	if !strings.Contains(rssStr, "<rss version=\"2.0\">") {
+		t.Fatalf("expected an RSS 2.0 root element, got:\n%s", rssStr)
+	}
+	wantItemLink := `<link>https://notations.dev/notations/releases/v1.2.0/</link>`
+	if !strings.Contains(rssStr, wantItemLink) {
+		t.Fatalf("expected item link to point at the rendered page (no .md, trailing slash), got:\n%s", rssStr)
+	}
+}
+
+func TestRenderedURLPathStripsExtensionAndCollapsesIndex(t *testing.T) {
+	cases := map[string]string{
+		"releases/v1.2.0.md": "releases/v1.2.0/",
+		"changelog/2026.mdx": "changelog/2026/",
+		"releases/index.md":  "releases/",
+		"index.md":           "",
+	}
+	for in, want := range cases {
+		if got := renderedURLPath(in); got != want {
+			t.Fatalf("renderedURLPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGeneratorNoEntriesIsNotAnError(t *testing.T) {
+	contentRoot := t.TempDir()
+	outputDir := t.TempDir()
+	site := &s3.Site{ContentRoot: contentRoot, OutputDir: outputDir, PathPrefix: "/notations"}
+	gen := NewGenerator(site, "notations.dev", "2024-01-01")
+
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() with no releases/changelog dir should be a no-op, got: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// xmlesc escapes s for safe inclusion in XML character data (text/template
+// has no XML auto-escaping, unlike html/template for HTML).
+func xmlesc(s string) (string, error) {
+	var buf bytes.Buffer
+	if err := xml.EscapeText(&buf, []byte(s)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var templateFuncs = template.FuncMap{"xmlesc": xmlesc}
+
+var atomTemplate = template.Must(template.New("atom").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="utf-8"?>
+{{if .StylesheetURL}}<?xml-stylesheet type="text/xsl" href="{{.StylesheetURL}}"?>
+{{end}}<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>{{xmlesc .Title}}</title>
+  <id>tag:{{.Domain}},{{.StartDate}}:feed</id>
+  <link href="{{.SiteURL}}/feed.atom" rel="self"/>
+  <link href="{{.SiteURL}}/"/>
+  <updated>{{.Updated}}</updated>
+  {{range .Entries}}<entry>
+    <title>{{xmlesc .Title}}</title>
+    <id>{{.ID}}</id>
+    <link href="{{$.SiteURL}}/{{.URLPath}}"/>
+    <updated>{{.Date.Format "2006-01-02T15:04:05Z07:00"}}</updated>
+    <summary>{{xmlesc .Summary}}</summary>
+    <content type="html">{{xmlesc (printf "%s" .Content)}}</content>
+  </entry>
+  {{end}}</feed>
+`))
+
+var rssTemplate = template.Must(template.New("rss").Funcs(templateFuncs).Parse(`<?xml version="1.0" encoding="utf-8"?>
+{{if .StylesheetURL}}<?xml-stylesheet type="text/xsl" href="{{.StylesheetURL}}"?>
+{{end}}<rss version="2.0">
+<channel>
+  <title>{{xmlesc .Title}}</title>
+  <link>{{.SiteURL}}/</link>
+  <description>{{xmlesc .Title}}</description>
+  <lastBuildDate>{{.Updated}}</lastBuildDate>
+  {{range .Entries}}<item>
+    <title>{{xmlesc .Title}}</title>
+    <link>{{$.SiteURL}}/{{.URLPath}}</link>
+    <guid isPermaLink="false">{{.ID}}</guid>
+    <pubDate>{{.Date.Format "Mon, 02 Jan 2006 15:04:05 -0700"}}</pubDate>
+    <description>{{xmlesc .Summary}}</description>
+  </item>
+  {{end}}</channel>
+</rss>
+`))
+
+// feedData is the shared view model for both the Atom and RSS templates.
+type feedData struct {
+	Title         string
+	Domain        string
+	StartDate     string
+	SiteURL       string
+	StylesheetURL string
+	Updated       string
+	Entries       []Entry
+}
+
+func (g *Generator) feedData(entries []Entry) feedData {
+	updated := ""
+	if len(entries) > 0 {
+		updated = entries[0].Date.Format("2006-01-02T15:04:05Z07:00")
+	}
+	title := g.Title
+	if title == "" {
+		title = g.Domain + g.Site.PathPrefix
+	}
+	return feedData{
+		Title:         title,
+		Domain:        g.Domain,
+		StartDate:     g.StartDate,
+		SiteURL:       "https://" + g.Domain + g.Site.PathPrefix,
+		StylesheetURL: g.StylesheetURL,
+		Updated:       updated,
+		Entries:       entries,
+	}
+}
+
+func (g *Generator) writeAtom(entries []Entry) error {
+	return g.writeFeed("feed.atom", atomTemplate, entries)
+}
+
+func (g *Generator) writeRSS(entries []Entry) error {
+	return g.writeFeed("feed.xml", rssTemplate, entries)
+}
+
+func (g *Generator) writeFeed(filename string, tmpl *template.Template, entries []Entry) error {
+	outpath := filepath.Join(g.Site.OutputDir, filename)
+	if err := os.MkdirAll(filepath.Dir(outpath), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(outpath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.Execute(f, g.feedData(entries))
+}
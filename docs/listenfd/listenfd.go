@@ -0,0 +1,76 @@
+// Package listenfd lets the docs server accept a pre-opened listening socket
+// instead of always calling net.Listen itself, so it can be run under
+// systemd socket activation (or a supervisor like s6/catatonit) and reloaded
+// without dropping in-flight connections.
+package listenfd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// firstListenFD is SD_LISTEN_FDS_START: systemd always hands activated
+// sockets starting at fd 3, after stdin/stdout/stderr.
+const firstListenFD = 3
+
+// Listen returns a net.Listener for addr, preferring (in order):
+//  1. an explicit file descriptor, if fd >= 0 (wired to the -listenfd flag)
+//  2. a systemd LISTEN_FDS/LISTEN_PID socket-activation handoff
+//  3. a plain net.Listen("tcp", addr)
+func Listen(addr string, fd int) (net.Listener, error) {
+	if fd >= 0 {
+		return listenerFromFD(fd)
+	}
+	if ln, ok, err := fromSystemd(); ok {
+		return ln, err
+	}
+	return net.Listen("tcp", addr)
+}
+
+// listenerFromFD wraps an already-open, already-listening socket fd as a
+// net.Listener.
+func listenerFromFD(fd int) (net.Listener, error) {
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("listenfd-%d", fd))
+	if f == nil {
+		return nil, fmt.Errorf("listenfd: fd %d is not valid", fd)
+	}
+	// net.FileListener dups f internally, so our copy can be closed once it
+	// has done so.
+	defer f.Close()
+
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("listenfd: fd %d: %w", fd, err)
+	}
+	return ln, nil
+}
+
+// fromSystemd implements the sd_listen_fds socket activation protocol: if
+// LISTEN_PID matches our pid and LISTEN_FDS names at least one socket, the
+// first one (fd 3) is used. See
+// https://www.freedesktop.org/software/systemd/man/sd_listen_fds.html
+func fromSystemd() (ln net.Listener, ok bool, err error) {
+	pidStr, fdsStr := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pidStr == "" || fdsStr == "" {
+		return nil, false, nil
+	}
+	// Whether or not it turns out to apply to us, these are only ever
+	// meaningful for the direct child systemd activated - unset them so a
+	// process we spawn doesn't also try to consume them.
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+
+	pid, err := strconv.Atoi(pidStr)
+	if err != nil || pid != os.Getpid() {
+		return nil, false, nil
+	}
+	numFDs, err := strconv.Atoi(fdsStr)
+	if err != nil || numFDs < 1 {
+		return nil, false, nil
+	}
+
+	ln, err = listenerFromFD(firstListenFD)
+	return ln, true, err
+}
@@ -0,0 +1,72 @@
+package listenfd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// dupListenerFD opens a real TCP listener and returns a duplicated file
+// descriptor for it, for use as a stand-in for a systemd-activated socket.
+func dupListenerFD(t *testing.T) (fd int, cleanup func()) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tcpListener := l.(*net.TCPListener)
+	f, err := tcpListener.File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tcpListener.File() already dup'd the fd; we can close the original now.
+	l.Close()
+	return int(f.Fd()), func() { f.Close() }
+}
+
+func TestListenFromExplicitFD(t *testing.T) {
+	fd, cleanup := dupListenerFD(t)
+	defer cleanup()
+
+	ln, err := Listen("ignored:0", fd)
+	if err != nil {
+		t.Fatalf("Listen with explicit fd failed: %v", err)
+	}
+	defer ln.Close()
+
+	if ln.Addr().Network() != "tcp" {
+		t.Fatalf("expected a tcp listener, got %s", ln.Addr().Network())
+	}
+}
+
+func TestFromSystemdIgnoredWhenPidMismatched(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+
+	_, ok, err := fromSystemd()
+	if ok || err != nil {
+		t.Fatalf("expected fromSystemd to decline on pid mismatch, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestFromSystemdIgnoredWhenUnset(t *testing.T) {
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+
+	_, ok, err := fromSystemd()
+	if ok || err != nil {
+		t.Fatalf("expected fromSystemd to decline when env vars are unset, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestListenFallsBackToNetListen(t *testing.T) {
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+
+	ln, err := Listen("127.0.0.1:0", -1)
+	if err != nil {
+		t.Fatalf("Listen fallback failed: %v", err)
+	}
+	defer ln.Close()
+}
@@ -3,15 +3,25 @@ package main
 import (
 	"flag"
 	"log"
+	"net/http"
 	"os"
 	"reflect"
 
+	"github.com/gorilla/mux"
 	s3 "github.com/panyam/s3gen"
+
+	"github.com/panyam/notations/docs/feed"
+	"github.com/panyam/notations/docs/srcbrowser"
 )
 
 var (
-	addr  = flag.String("addr", DefaultAddress(), "Address where the http server is running")
-	build = flag.Bool("build", false, "Builds the latest site and quits instead of running a server to serve it")
+	addr        = flag.String("addr", DefaultAddress(), "Address where the http server is running")
+	build       = flag.Bool("build", false, "Builds the latest site and quits instead of running a server to serve it")
+	vanityConf  = flag.String("vanity_config", DefaultVanityConfigPath(), "Path to the vanity import path config, or empty to disable it")
+	srcRoot     = flag.String("src_root", DefaultSrcRoot(), "Path to the notations repo to serve under /src/")
+	feedDomain  = flag.String("feed_domain", "notations.dev", "Domain used in feed entry tag URIs")
+	feedStarted = flag.String("feed_start_date", "2024-01-01", "Date (YYYY-MM-DD) anchoring feed entry tag URIs")
+	listenFD    = flag.Int("listenfd", -1, "File descriptor of a pre-opened listening socket (overrides -addr and systemd socket activation)")
 )
 
 // Site configuration for Notations Library Documentation
@@ -48,15 +58,39 @@ var Site = &s3.Site{
 func main() {
 	flag.Parse()
 	log.Println("Build: ", *build, reflect.TypeOf(*build))
+	run()
+}
+
+// buildHandler assembles the http.Handler chain shared by the dev and prod
+// binaries: the VanityHandler (if configured) in front of the s3gen site mux.
+func buildHandler() http.Handler {
+	router := mux.NewRouter()
 
-	// In development mode, enable live reloading
-	if *build || os.Getenv("NOTATIONS_DOCS_ENV") != "production" {
-		Site.Rebuild(nil)
-		Site.Watch()
+	// Registered before the catch-all site handler below so its more
+	// specific prefix (PathPrefix + "/src") wins.
+	srcbrowser.New(*srcRoot, Site.PathPrefix+"/src", Site).Mount(router)
+
+	router.PathPrefix(Site.PathPrefix).Handler(http.StripPrefix(Site.PathPrefix, Site))
+
+	var handler http.Handler = router
+	if *vanityConf != "" {
+		config, err := LoadVanityConfig(*vanityConf)
+		if err != nil {
+			log.Println("Vanity config disabled, could not load: ", *vanityConf, err)
+		} else {
+			handler = NewVanityHandler(config, router)
+		}
 	}
+	return handler
+}
 
-	if !*build {
-		Site.Serve(*addr)
+// generateFeeds renders feed.atom/feed.xml for the site's release notes and
+// changelog entries. Called once, after the initial Site.Rebuild in run;
+// rebuilds triggered later by Site.Watch do not regenerate the feeds.
+func generateFeeds() {
+	gen := feed.NewGenerator(Site, *feedDomain, *feedStarted)
+	if err := gen.Generate(); err != nil {
+		log.Println("Error generating feeds: ", err)
 	}
 }
 
@@ -67,3 +101,21 @@ func DefaultAddress() string {
 	}
 	return ":8080"
 }
+
+// DefaultVanityConfigPath returns the path to the vanity import config, which
+// can be overridden via NOTATIONS_DOCS_VANITY_CONFIG.
+func DefaultVanityConfigPath() string {
+	if p := os.Getenv("NOTATIONS_DOCS_VANITY_CONFIG"); p != "" {
+		return p
+	}
+	return "./vanity.yaml"
+}
+
+// DefaultSrcRoot returns the directory served under /src/, which can be
+// overridden via NOTATIONS_DOCS_SRC_ROOT.
+func DefaultSrcRoot() string {
+	if p := os.Getenv("NOTATIONS_DOCS_SRC_ROOT"); p != "" {
+		return p
+	}
+	return ".."
+}
@@ -0,0 +1,132 @@
+//go:build prod
+
+package main
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/panyam/notations/docs/listenfd"
+)
+
+// run implements the production flow: no file watcher, /debug/vars and
+// /debug/pprof mounted, structured access logging, and a graceful shutdown on
+// SIGINT/SIGTERM so in-flight requests get to drain before the process exits.
+func run() {
+	configureLogging()
+
+	Site.Rebuild(nil)
+	generateFeeds()
+	if *build {
+		return
+	}
+
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/debug/vars", expvar.Handler())
+	debugMux.HandleFunc("/debug/pprof/", pprof.Index)
+	debugMux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	debugMux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	debugMux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	debugMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	debugMux.Handle("/", accessLog(buildHandler()))
+
+	ln, err := listenfd.Listen(*addr, *listenFD)
+	if err != nil {
+		log.Fatal("Could not acquire a listener: ", err)
+	}
+
+	srv := &http.Server{
+		Handler:      debugMux,
+		ReadTimeout:  envSeconds("NOTATIONS_DOCS_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout: envSeconds("NOTATIONS_DOCS_WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:  envSeconds("NOTATIONS_DOCS_IDLE_TIMEOUT", 60*time.Second),
+	}
+
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		log.Printf("Serving site (prod) on %s", ln.Addr())
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-shutdown
+	log.Println("Shutdown signal received, draining connections...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), envSeconds("NOTATIONS_DOCS_SHUTDOWN_TIMEOUT", 15*time.Second))
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Println("Error during shutdown: ", err)
+	}
+}
+
+// configureLogging sets the minimum level of slog's top-level logging
+// functions from NOTATIONS_DOCS_LOG_LEVEL (debug, info, warn, error; defaults
+// to info). Plain log.Printf output, including accessLog's, is not filtered.
+func configureLogging() {
+	level := slog.LevelInfo
+	switch strings.ToLower(os.Getenv("NOTATIONS_DOCS_LOG_LEVEL")) {
+	case "debug":
+		level = slog.LevelDebug
+	case "warn", "warning":
+		level = slog.LevelWarn
+	case "error":
+		level = slog.LevelError
+	}
+	slog.SetLogLoggerLevel(level)
+}
+
+// accessLog logs method, path, status, response size and latency for every
+// request in a structured key=value form, suitable for container log
+// collectors.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("method=%s path=%s status=%d bytes=%d latency=%s",
+			r.Method, r.URL.Path, rec.status, rec.bytes, time.Since(start))
+	})
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code and
+// byte count written, for accessLog.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// envSeconds reads key as a whole number of seconds, falling back to def if
+// it is unset or invalid.
+func envSeconds(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return def
+}
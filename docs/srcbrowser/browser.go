@@ -0,0 +1,186 @@
+// Package srcbrowser serves a read-only, syntax-highlighted view of a
+// directory tree over HTTP, analogous to golang.org/src/. It is meant to be
+// mounted onto an existing gorilla/mux router alongside an *s3gen.Site, so
+// the highlighted source can be wrapped in the site's own BasePage.html.
+package srcbrowser
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	s3 "github.com/panyam/s3gen"
+)
+
+// PreviewFunc renders an inline preview for a source file, e.g. turning a
+// ".notation" file into the SVG/HTML its parser would produce. It is called
+// with the file's relative path (from Root) and raw contents.
+type PreviewFunc func(relpath string, content []byte) (template.HTML, error)
+
+// Browser serves Root as a browsable, syntax-highlighted source tree.
+type Browser struct {
+	// Root is the directory being served.
+	Root string
+
+	// PathPrefix is the URL path this Browser is mounted at, e.g. "/src".
+	// It is stripped from incoming requests before resolving against Root.
+	PathPrefix string
+
+	// Site, if set, is used to render pages inside the site's
+	// DefaultBaseTemplate (e.g. BasePage.html) instead of a bare HTML shell.
+	Site *s3.Site
+
+	// HighlightExts is the set of file extensions rendered as highlighted
+	// source (with a leading dot, e.g. ".go"). Anything else is served as-is
+	// via http.ServeContent, which takes care of MIME sniffing and range
+	// requests.
+	HighlightExts []string
+
+	// Preview, if set, is invoked for ".notation" files to render an inline
+	// preview above the highlighted source. New wires this to defaultPreview,
+	// which emits the <notation> tag notations-web renders client-side (the
+	// notations parser itself is a TypeScript/DOM library with no Go API).
+	Preview PreviewFunc
+}
+
+// New returns a Browser serving root, mounted at pathPrefix.
+func New(root, pathPrefix string, site *s3.Site) *Browser {
+	return &Browser{
+		Root:          root,
+		PathPrefix:    pathPrefix,
+		Site:          site,
+		HighlightExts: []string{".go", ".notation"},
+		Preview:       defaultPreview,
+	}
+}
+
+// Mount registers the Browser on router under its PathPrefix.
+func (b *Browser) Mount(router *mux.Router) {
+	router.PathPrefix(b.PathPrefix).Handler(http.StripPrefix(b.PathPrefix, b))
+}
+
+func (b *Browser) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fullpath, relpath, err := b.resolve(r.URL.Path)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	info, err := os.Stat(fullpath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if info.IsDir() {
+		b.serveDir(w, r, fullpath, relpath)
+		return
+	}
+
+	if b.highlighted(fullpath) {
+		b.serveHighlighted(w, r, fullpath, relpath, info)
+		return
+	}
+
+	// Anything else (assets alongside the source) is served as-is;
+	// http.ServeContent handles MIME sniffing, Last-Modified and ranges.
+	f, err := os.Open(fullpath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	http.ServeContent(w, r, fullpath, info.ModTime(), f)
+}
+
+// resolve maps a request path under PathPrefix to an absolute path under
+// Root, rejecting any attempt (via ".." or symlink-free traversal) to escape
+// Root.
+func (b *Browser) resolve(urlPath string) (fullpath, relpath string, err error) {
+	relpath = path.Clean("/" + urlPath)
+
+	rootAbs, err := filepath.Abs(b.Root)
+	if err != nil {
+		return "", "", err
+	}
+	fullAbs, err := filepath.Abs(filepath.Join(rootAbs, relpath))
+	if err != nil {
+		return "", "", err
+	}
+	if fullAbs != rootAbs && !strings.HasPrefix(fullAbs, rootAbs+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("path %q escapes root %q", urlPath, b.Root)
+	}
+	if hiddenSegment(relpath) {
+		return "", "", fmt.Errorf("path %q is hidden", urlPath)
+	}
+	return fullAbs, relpath, nil
+}
+
+// hiddenSegment reports whether any path segment of relpath starts with ".",
+// e.g. ".git" or ".env". Root is usually the notations repo checkout itself,
+// so this keeps VCS internals and dotfiles out of the browsable tree even
+// though nothing in the traversal guard above would otherwise stop them.
+func hiddenSegment(relpath string) bool {
+	for _, part := range strings.Split(relpath, "/") {
+		if part != "" && strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Browser) highlighted(fullpath string) bool {
+	ext := filepath.Ext(fullpath)
+	for _, e := range b.HighlightExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotModified sets Last-Modified and, if the client's cached copy is
+// still fresh, writes a 304 and returns true.
+func checkNotModified(w http.ResponseWriter, r *http.Request, modTime time.Time) bool {
+	w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "no-cache")
+	if since := r.Header.Get("If-Modified-Since"); since != "" {
+		if t, err := http.ParseTime(since); err == nil && !modTime.Truncate(time.Second).After(t) {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// renderInBasePage writes body, rendered inside site's DefaultBaseTemplate
+// when site is set, or a minimal standalone HTML shell otherwise.
+func renderInBasePage(w io.Writer, site *s3.Site, title string, body template.HTML) error {
+	if site == nil || site.Templates == nil || site.DefaultBaseTemplate.Name == "" {
+		_, err := fmt.Fprintf(w, "<!DOCTYPE html><html><head><title>%s</title></head><body>%s</body></html>", title, body)
+		return err
+	}
+
+	tmpls, err := site.Templates.Loader.Load(site.DefaultBaseTemplate.Name, "")
+	if err != nil {
+		return err
+	}
+
+	params := map[any]any{
+		"Site":    site,
+		"Title":   title,
+		"Content": body,
+	}
+	if site.DefaultBaseTemplate.Params != nil {
+		maps.Copy(params, site.DefaultBaseTemplate.Params)
+	}
+	return site.Templates.RenderHtmlTemplate(w, tmpls[0], site.DefaultBaseTemplate.Entry, params, nil)
+}
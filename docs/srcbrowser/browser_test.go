@@ -0,0 +1,187 @@
+package srcbrowser
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBrowser(t *testing.T) (*Browser, string) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "song.notation"), []byte("|C D E F|\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "readme.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".git", "config"), []byte("[core]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("SECRET=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return New(root, "/src", nil), root
+}
+
+func TestBrowserRejectsPathTraversal(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/src/../../../../etc/passwd", nil)
+	req.URL.Path = "/../../../../etc/passwd"
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected traversal attempt to be rejected with 404, got %d", rec.Code)
+	}
+}
+
+func TestBrowserServesDirectoryListing(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/sub", nil)
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "readme.txt") {
+		t.Fatalf("expected listing to mention readme.txt, got:\n%s", rec.Body.String())
+	}
+}
+
+func TestBrowserRootListingHidesDotfiles(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), ".git") || strings.Contains(rec.Body.String(), ".env") {
+		t.Fatalf("expected dotfiles hidden from listing, got:\n%s", rec.Body.String())
+	}
+}
+
+func TestBrowserRefusesDirectDotfileAccess(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	for _, p := range []string{"/.git/config", "/.env"} {
+		req := httptest.NewRequest(http.MethodGet, p, nil)
+		rec := httptest.NewRecorder()
+		b.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected %s to be refused with 404, got %d: %s", p, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestBrowserHighlightsGoSource(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/main.go", nil)
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "srcbrowser-source") {
+		t.Fatalf("expected highlighted source wrapper in body, got:\n%s", rec.Body.String())
+	}
+	if rec.Header().Get("Last-Modified") == "" {
+		t.Fatalf("expected Last-Modified header on highlighted file response")
+	}
+}
+
+func TestBrowserHighlightedFileSupportsNotModified(t *testing.T) {
+	b, root := newTestBrowser(t)
+	info, err := os.Stat(filepath.Join(root, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/main.go", nil)
+	req.Header.Set("If-Modified-Since", info.ModTime().Add(time.Second).UTC().Format(http.TimeFormat))
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+}
+
+func TestBrowserSniffsMimeForOtherFiles(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/sub/readme.txt", nil)
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	ct := rec.Header().Get("Content-Type")
+	if !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected sniffed text/plain content type, got %q", ct)
+	}
+}
+
+func TestBrowserPreviewHookForNotationFiles(t *testing.T) {
+	b, _ := newTestBrowser(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/song.notation", nil)
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `<notation id="notation-song-notation"`) {
+		t.Fatalf("expected a <notation> tag for the default preview, got:\n%s", body)
+	}
+	if !strings.Contains(body, "|C D E F|") {
+		t.Fatalf("expected the raw source inside the <notation> tag, got:\n%s", body)
+	}
+	if !strings.Contains(body, `import { NotationBlock } from "https://esm.sh/notations-web";`) {
+		t.Fatalf("expected the notations-web bootstrap script, got:\n%s", body)
+	}
+}
+
+func TestBrowserPreviewHookCanBeOverridden(t *testing.T) {
+	b, _ := newTestBrowser(t)
+	b.Preview = func(relpath string, content []byte) (template.HTML, error) {
+		return template.HTML("<p>custom preview for " + relpath + "</p>"), nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/song.notation", nil)
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "<p>custom preview for /song.notation</p>") {
+		t.Fatalf("expected the overridden preview to be used, got:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package srcbrowser
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// defaultPreview is the Preview wired in by New() for ".notation" files.
+//
+// notations and notations-web (github.com/panyam/notations) are TypeScript
+// packages: the actual parser and SVG/HTML renderer run as DOM-bound Web
+// Components (NotationBlock) and have no Go-callable or server-side entry
+// point. So instead of parsing the score ourselves, we emit the <notation>
+// tag the notations-web README documents and a small client-side bootstrap
+// script that imports notations-web/notations from a CDN and upgrades it,
+// mirroring the package's own documented usage.
+func defaultPreview(relpath string, content []byte) (template.HTML, error) {
+	id := notationElementID(relpath)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<notation id=%q showSource=\"false\">\n", id)
+	buf.WriteString(template.HTMLEscapeString(string(content)))
+	buf.WriteString("\n</notation>\n")
+	buf.WriteString(notationBootstrapScript)
+	return template.HTML(buf.String()), nil
+}
+
+// notationElementID derives a stable, HTML-id-safe identifier from relpath
+// for the <notation> tag's id attribute.
+func notationElementID(relpath string) string {
+	replacer := strings.NewReplacer("/", "-", ".", "-")
+	return "notation-" + strings.Trim(replacer.Replace(relpath), "-")
+}
+
+// notationBootstrapScript finds any <notation> tags rendered into the page
+// and upgrades them via notations-web's NotationBlock component, per
+// https://github.com/panyam/notations/blob/main/src/web/README.md.
+const notationBootstrapScript = `<script type="module">
+import { NotationBlock } from "https://esm.sh/notations-web";
+import * as N from "https://esm.sh/notations";
+
+function createViewer(container) {
+  const table = document.createElement("table");
+  table.classList.add("notation-table");
+  container.appendChild(table);
+  return new N.Carnatic.NotationView(table);
+}
+
+document.querySelectorAll("notation").forEach((el) => {
+  if (!el.dataset.notationBlockMounted) {
+    new NotationBlock(el, { createViewer });
+    el.dataset.notationBlockMounted = "true";
+  }
+});
+</script>
+`
@@ -0,0 +1,147 @@
+package srcbrowser
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/quick"
+)
+
+// dirEntryView is the data passed to the directory listing template.
+type dirEntryView struct {
+	Name    string
+	Href    string
+	IsDir   bool
+	Size    int64
+	ModTime string
+}
+
+var dirTemplate = template.Must(template.New("srcbrowser-dir").Parse(`
+<h1>{{.Title}}</h1>
+<table class="srcbrowser-listing">
+<thead><tr><th>Name</th><th>Size</th><th>Modified</th></tr></thead>
+<tbody>
+{{if .HasParent}}<tr><td><a href="{{.ParentHref}}">..</a></td><td></td><td></td></tr>{{end}}
+{{range .Entries}}<tr><td><a href="{{.Href}}">{{.Name}}{{if .IsDir}}/{{end}}</a></td><td>{{if not .IsDir}}{{.Size}}{{end}}</td><td>{{.ModTime}}</td></tr>
+{{end}}
+</tbody>
+</table>
+`))
+
+func (b *Browser) serveDir(w http.ResponseWriter, r *http.Request, fullpath, relpath string) {
+	entries, err := os.ReadDir(fullpath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	var views []dirEntryView
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			// Keep dotfiles/dirs (.git, .env, ...) out of the listing, even
+			// though resolve() already refuses to serve them directly.
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		views = append(views, dirEntryView{
+			Name:    e.Name(),
+			Href:    path.Join(b.PathPrefix, relpath, e.Name()),
+			IsDir:   e.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime().Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	body := bytes.NewBufferString("")
+	data := struct {
+		Title      string
+		HasParent  bool
+		ParentHref string
+		Entries    []dirEntryView
+	}{
+		Title:     relpath,
+		HasParent: relpath != "/" && relpath != ".",
+	}
+	if data.HasParent {
+		data.ParentHref = path.Join(b.PathPrefix, path.Dir(relpath))
+	}
+	data.Entries = views
+	if err := dirTemplate.Execute(body, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := renderInBasePage(w, b.Site, relpath, template.HTML(body.String())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (b *Browser) serveHighlighted(w http.ResponseWriter, r *http.Request, fullpath, relpath string, info os.FileInfo) {
+	if checkNotModified(w, r, info.ModTime()) {
+		return
+	}
+
+	source, err := os.ReadFile(fullpath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var preview template.HTML
+	if b.Preview != nil && path.Ext(relpath) == ".notation" {
+		preview, err = b.Preview(relpath, source)
+		if err != nil {
+			preview = template.HTML("<p class=\"srcbrowser-preview-error\">preview error: " + template.HTMLEscapeString(err.Error()) + "</p>")
+		}
+	}
+
+	highlighted := bytes.NewBufferString("")
+	lexer := lexerFor(relpath)
+	if err := quick.Highlight(highlighted, string(source), lexer, "html", "monokai"); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body := bytes.NewBufferString("")
+	body.WriteString("<h1>" + template.HTMLEscapeString(relpath) + "</h1>")
+	if preview != "" {
+		body.WriteString("<div class=\"srcbrowser-preview\">")
+		body.WriteString(string(preview))
+		body.WriteString("</div>")
+	}
+	body.WriteString("<div class=\"srcbrowser-source\">")
+	body.WriteString(highlighted.String())
+	body.WriteString("</div>")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := renderInBasePage(w, b.Site, relpath, template.HTML(body.String())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// lexerFor returns the chroma lexer name for a file's extension, leaving
+// unrecognized extensions (like ".notation") to chroma's own content-based
+// analysis and eventual plaintext fallback.
+func lexerFor(relpath string) string {
+	switch path.Ext(relpath) {
+	case ".go":
+		return "go"
+	default:
+		return ""
+	}
+}
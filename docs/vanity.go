@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// VanityModule describes how a single module subpath should be reported to
+// `go get`. Subpath is relative to VanityConfig.Host and is also used as the
+// key in VanityConfig.Modules (e.g. "" for the root module, "docs" for
+// "notations.dev/docs").
+type VanityModule struct {
+	// Repo is the VCS repository root, e.g. "https://github.com/panyam/notations".
+	Repo string `yaml:"repo"`
+
+	// VCS is the version control system hosting Repo. Defaults to "git".
+	VCS string `yaml:"vcs"`
+
+	// GodocPath overrides the import path used when redirecting browsers to
+	// pkg.go.dev. Defaults to Host + "/" + subpath.
+	GodocPath string `yaml:"godoc_path"`
+}
+
+// VanityConfig maps module subpaths under Host to their backing repositories.
+// It is loaded from a YAML file, e.g.:
+//
+//	host: notations.dev
+//	modules:
+//	  "": {repo: "https://github.com/panyam/notations", vcs: git}
+//	  docs: {repo: "https://github.com/panyam/notations", vcs: git}
+type VanityConfig struct {
+	// Host is the domain serving as the vanity import path, e.g. "notations.dev".
+	Host string `yaml:"host"`
+
+	// Modules maps subpaths (no leading/trailing slash, "" for the root) to
+	// their VanityModule definition.
+	Modules map[string]VanityModule `yaml:"modules"`
+}
+
+// LoadVanityConfig reads and parses a VanityConfig from a YAML file at path.
+func LoadVanityConfig(path string) (*VanityConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out VanityConfig
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing vanity config %s: %w", path, err)
+	}
+	for subpath, mod := range out.Modules {
+		if mod.VCS == "" {
+			mod.VCS = "git"
+		}
+		out.Modules[subpath] = mod
+	}
+	return &out, nil
+}
+
+// Match finds the module covering urlPath, returning the matched subpath and
+// its VanityModule. The longest registered subpath that is a prefix of
+// urlPath wins, so a more specific entry (e.g. "docs") takes precedence over
+// the root module ("").
+func (c *VanityConfig) Match(urlPath string) (subpath string, mod VanityModule, ok bool) {
+	trimmed := strings.Trim(urlPath, "/")
+
+	var candidates []string
+	for sp := range c.Modules {
+		if sp == "" || trimmed == sp || strings.HasPrefix(trimmed, sp+"/") {
+			candidates = append(candidates, sp)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", VanityModule{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool { return len(candidates[i]) > len(candidates[j]) })
+	best := candidates[0]
+	return best, c.Modules[best], true
+}
+
+// ImportPath returns the full vanity import path for subpath under c.Host.
+func (c *VanityConfig) ImportPath(subpath string) string {
+	if subpath == "" {
+		return c.Host
+	}
+	return c.Host + "/" + subpath
+}
+
+// VanityHandler intercepts `?go-get=1` requests for configured module
+// subpaths and returns the `go-import`/`go-source` meta tags `go get` needs
+// to resolve notations.dev/... as a vanity path for the real GitHub repo.
+// All other requests are passed through to Next, except for a small table of
+// well-known browser paths that are redirected to the rendered godoc page.
+type VanityHandler struct {
+	Config *VanityConfig
+	Next   http.Handler
+}
+
+// NewVanityHandler returns a VanityHandler serving config, falling through to
+// next for anything it doesn't recognize.
+func NewVanityHandler(config *VanityConfig, next http.Handler) *VanityHandler {
+	return &VanityHandler{Config: config, Next: next}
+}
+
+func (v *VanityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subpath, mod, ok := v.Config.Match(r.URL.Path)
+	if !ok {
+		v.Next.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Query().Get("go-get") == "1" {
+		writeGoImportMeta(w, v.Config, subpath, mod)
+		return
+	}
+
+	if dest, ok := wellKnownDocsRedirect(v.Config, subpath, mod, r.URL.Path); ok {
+		http.Redirect(w, r, dest, http.StatusFound)
+		return
+	}
+
+	v.Next.ServeHTTP(w, r)
+}
+
+// writeGoImportMeta writes the minimal HTML document `go get` expects,
+// containing the go-import and go-source meta tags for the matched module.
+func writeGoImportMeta(w http.ResponseWriter, c *VanityConfig, subpath string, mod VanityModule) {
+	importPath := c.ImportPath(subpath)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+<meta name="go-import" content="%s %s %s">
+<meta name="go-source" content="%s %s %s/tree/main{/dir} %s/blob/main{/dir}/{file}#L{line}">
+</head>
+<body>
+go get %s
+</body>
+</html>
+`, importPath, mod.VCS, mod.Repo, importPath, mod.Repo, mod.Repo, mod.Repo, importPath)
+}
+
+// wellKnownDocsRedirect maps the bare "/<subpath>" of a non-root module, the
+// path browsers commonly land on, to the corresponding pkg.go.dev page, so a
+// human following a `go get` failure link sees something useful. The bare
+// site root is never redirected.
+func wellKnownDocsRedirect(c *VanityConfig, subpath string, mod VanityModule, urlPath string) (string, bool) {
+	trimmed := strings.Trim(urlPath, "/")
+	if trimmed != subpath {
+		return "", false
+	}
+	if trimmed == "" {
+		// The root module ("") matches every path via Match's fallback, so a
+		// plain hit on the bare site root would otherwise always satisfy
+		// trimmed == subpath above and get redirected off-site instead of
+		// falling through to serve the real site.
+		return "", false
+	}
+	godocPath := mod.GodocPath
+	if godocPath == "" {
+		godocPath = c.ImportPath(subpath)
+	}
+	return "https://pkg.go.dev/" + godocPath, true
+}
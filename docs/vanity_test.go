@@ -0,0 +1,117 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testConfig() *VanityConfig {
+	return &VanityConfig{
+		Host: "notations.dev",
+		Modules: map[string]VanityModule{
+			"":     {Repo: "https://github.com/panyam/notations", VCS: "git"},
+			"docs": {Repo: "https://github.com/panyam/notations", VCS: "git"},
+		},
+	}
+}
+
+func TestVanityConfigMatch(t *testing.T) {
+	c := testConfig()
+
+	subpath, _, ok := c.Match("/unrelated")
+	if !ok || subpath != "" {
+		t.Fatalf("expected /unrelated to fall back to the root module, got subpath=%q ok=%v", subpath, ok)
+	}
+
+	subpath, mod, ok := c.Match("/docs/guide")
+	if !ok || subpath != "docs" {
+		t.Fatalf("expected /docs/guide to match the docs module, got subpath=%q ok=%v", subpath, ok)
+	}
+	if mod.Repo != "https://github.com/panyam/notations" {
+		t.Fatalf("unexpected repo: %q", mod.Repo)
+	}
+
+	subpath, _, ok = c.Match("/something")
+	if !ok || subpath != "" {
+		t.Fatalf("expected /something to fall back to the root module, got subpath=%q ok=%v", subpath, ok)
+	}
+}
+
+func TestVanityHandlerGoGetMeta(t *testing.T) {
+	c := testConfig()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("next handler should not be called for a go-get request")
+	})
+	h := NewVanityHandler(c, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/docs?go-get=1", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	wantImport := `<meta name="go-import" content="notations.dev/docs git https://github.com/panyam/notations">`
+	if !strings.Contains(body, wantImport) {
+		t.Fatalf("response missing go-import meta tag, got:\n%s", body)
+	}
+	if !strings.Contains(body, `<meta name="go-source"`) {
+		t.Fatalf("response missing go-source meta tag, got:\n%s", body)
+	}
+}
+
+func TestVanityHandlerFallsThrough(t *testing.T) {
+	c := &VanityConfig{Host: "notations.dev", Modules: map[string]VanityModule{}}
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	h := NewVanityHandler(c, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/notations/index.html", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !called {
+		t.Fatalf("expected request to fall through to next handler")
+	}
+}
+
+func TestVanityHandlerRootFallsThroughWithRootModuleConfigured(t *testing.T) {
+	c := testConfig()
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	h := NewVanityHandler(c, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if !called {
+		t.Fatalf("expected a bare browser hit on / to fall through to next, not redirect off-site")
+	}
+	if rec.Code == http.StatusFound {
+		t.Fatalf("expected no redirect for the bare site root, got %d to %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestVanityHandlerBrowserRedirect(t *testing.T) {
+	c := testConfig()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("next handler should not be called for a bare browser hit")
+	})
+	h := NewVanityHandler(c, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("expected a redirect, got status %d", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "https://pkg.go.dev/notations.dev/docs" {
+		t.Fatalf("unexpected redirect location: %q", loc)
+	}
+}